@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tjgq/sane"
+	"golang.org/x/image/tiff"
+)
+
+// runBatchScan drives an unattended batch scan described by a JobSpec.
+// For ADF sources it keeps calling c.ReadImage() until SANE reports
+// end-of-feed (io.EOF), collecting one image per page. Flatbed jobs
+// read a single page. Pages are then written out either as individually
+// numbered files (page-001.png, ...) or as a single multi-page file,
+// depending on job.Output.
+func runBatchScan(c *sane.Conn, job *JobSpec) error {
+	if err := parseOptions(c, job.toOptions()); err != nil {
+		return err
+	}
+
+	var pages []image.Image
+	for i := 1; ; i++ {
+		img, err := c.ReadImage()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("page %d: %w", i, err)
+		}
+		processed, keep, err := job.pipeline().Apply(img)
+		if err != nil {
+			return fmt.Errorf("page %d: %w", i, err)
+		}
+		if !keep {
+			continue
+		}
+		pages = append(pages, processed)
+
+		if job.Output == "pages" {
+			if err := writePage(job, len(pages), processed); err != nil {
+				return err
+			}
+		}
+		if job.Source != "adf" && job.Source != "adf-duplex" {
+			break
+		}
+	}
+
+	if job.Output != "pages" {
+		return writeMultiPage(job, pages)
+	}
+	return nil
+}
+
+func writePage(job *JobSpec, n int, img image.Image) error {
+	name := fmt.Sprintf("page-%03d.%s", n, job.Format)
+	path := filepath.Join(job.Dest, name)
+
+	enc, err := pathToEncoder(path, job.OCR, job.G4)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return enc(f, img)
+}
+
+func writeMultiPage(job *JobSpec, pages []image.Image) error {
+	if len(pages) == 0 {
+		return fmt.Errorf("no pages scanned")
+	}
+
+	f, err := os.Create(job.Dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(job.Format) {
+	case "tif", "tiff":
+		for _, p := range pages {
+			if err := tiff.Encode(f, p, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported multi-page output format %s", job.Format)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteMultiPageNoPages(t *testing.T) {
+	job := &JobSpec{Format: "tiff", Dest: filepath.Join(t.TempDir(), "out.tiff")}
+
+	if err := writeMultiPage(job, nil); err == nil {
+		t.Error("writeMultiPage() error = nil, want an error for zero pages")
+	}
+}
+
+func TestWriteMultiPageUnsupportedFormat(t *testing.T) {
+	job := &JobSpec{Format: "png", Dest: filepath.Join(t.TempDir(), "out.png")}
+	pages := []image.Image{image.NewGray(image.Rect(0, 0, 1, 1))}
+
+	if err := writeMultiPage(job, pages); err == nil {
+		t.Error("writeMultiPage() error = nil, want an error for a format multi-page output doesn't support")
+	}
+}
+
+func TestWritePage(t *testing.T) {
+	dir := t.TempDir()
+	job := &JobSpec{Format: "png", Dest: dir}
+	img := image.NewGray(image.Rect(0, 0, 2, 2))
+
+	if err := writePage(job, 1, img); err != nil {
+		t.Fatalf("writePage() error = %v", err)
+	}
+
+	path := filepath.Join(dir, "page-001.png")
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("writePage() did not create %s: %v", path, err)
+	}
+}
@@ -0,0 +1,77 @@
+// Package config loads named scan profiles from a TOML or YAML file so
+// resolution, mode and post-processing can be changed without
+// recompiling: a [profile.receipt] or [profile.photo] section per use
+// case, plus a [device.<pattern>] section mapping a device-name
+// substring (the same matching openDevice already does) to its default
+// profile.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the top-level shape of a config file: named profiles plus
+// the device-pattern -> profile-name defaults.
+type Config struct {
+	Profile map[string]Profile `toml:"profile" yaml:"profile"`
+	Device  map[string]string  `toml:"device" yaml:"device"`
+}
+
+// Load reads a Config from a .toml, .yaml or .yml file.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	var cfg Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		if err := toml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parse config: %w", err)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parse config: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unrecognized config extension %s", path)
+	}
+	return &cfg, nil
+}
+
+// ProfileForDevice returns the profile configured for the longest
+// [device.<pattern>] entry whose pattern is a substring of name,
+// mirroring the substring matching openDevice uses to resolve a device.
+// The longest pattern wins so a more specific entry (e.g. "ScanSnap
+// ix500") takes precedence over a more general one (e.g. "ScanSnap")
+// that also matches; map iteration order is otherwise random, so ties
+// are broken by sorting the candidate patterns.
+func (c *Config) ProfileForDevice(name string) (Profile, bool) {
+	var patterns []string
+	for pattern := range c.Device {
+		if strings.Contains(name, pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if len(patterns) == 0 {
+		return Profile{}, false
+	}
+	sort.Strings(patterns)
+	best := patterns[0]
+	for _, pattern := range patterns[1:] {
+		if len(pattern) > len(best) {
+			best = pattern
+		}
+	}
+
+	p, ok := c.Profile[c.Device[best]]
+	return p, ok
+}
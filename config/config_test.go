@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileForDeviceLongestMatchWins(t *testing.T) {
+	cfg := &Config{
+		Profile: map[string]Profile{
+			"generic":  {Resolution: 300},
+			"specific": {Resolution: 600},
+		},
+		Device: map[string]string{
+			"ScanSnap":       "generic",
+			"ScanSnap iX500": "specific",
+		},
+	}
+
+	p, ok := cfg.ProfileForDevice("ScanSnap iX500 network scanner")
+	if !ok {
+		t.Fatal("ProfileForDevice() found no match, want the longest matching pattern")
+	}
+	if p.Resolution != 600 {
+		t.Errorf("ProfileForDevice() resolution = %d, want 600 from the more specific pattern", p.Resolution)
+	}
+}
+
+func TestProfileForDeviceNoMatch(t *testing.T) {
+	cfg := &Config{
+		Profile: map[string]Profile{"generic": {Resolution: 300}},
+		Device:  map[string]string{"Canon": "generic"},
+	}
+
+	if _, ok := cfg.ProfileForDevice("Epson V600"); ok {
+		t.Error("ProfileForDevice() matched a device with no configured pattern")
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	data := []byte(`
+[profile.receipt]
+resolution = 300
+mode = "gray"
+
+[device]
+"ScanSnap" = "receipt"
+`)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write test config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	p, ok := cfg.Profile["receipt"]
+	if !ok {
+		t.Fatal("Load() did not parse the [profile.receipt] section")
+	}
+	if p.Resolution != 300 || p.Mode != "gray" {
+		t.Errorf("Load() profile = %+v, want Resolution=300 Mode=gray", p)
+	}
+	if cfg.Device["ScanSnap"] != "receipt" {
+		t.Errorf("Load() device mapping = %q, want %q", cfg.Device["ScanSnap"], "receipt")
+	}
+}
+
+func TestLoadUnrecognizedExtension(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.ini")
+	if err := os.WriteFile(path, []byte("resolution=300"), 0o644); err != nil {
+		t.Fatalf("write test config: %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Error("Load() of an unrecognized extension returned nil error, want one")
+	}
+}
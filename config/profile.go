@@ -0,0 +1,47 @@
+package config
+
+// Profile is a named set of scan parameters, e.g. [profile.receipt] or
+// [profile.photo]: SANE option values, the output format and the
+// imgproc post-processing steps to run.
+type Profile struct {
+	Resolution     int      `toml:"resolution" yaml:"resolution"`
+	Mode           string   `toml:"mode" yaml:"mode"`
+	Source         string   `toml:"source" yaml:"source"`
+	Format         string   `toml:"format" yaml:"format"`
+	PostProcess    []string `toml:"postProcess" yaml:"postProcess"`
+	BlankThreshold float64  `toml:"blankThreshold" yaml:"blankThreshold"`
+	OCR            bool     `toml:"ocr" yaml:"ocr"`
+	G4             bool     `toml:"g4" yaml:"g4"`
+}
+
+// Merge returns profile with every non-zero field of override applied
+// on top, so a request's per-call overrides win over the named
+// profile's defaults without having to repeat the whole profile.
+func (p Profile) Merge(override Profile) Profile {
+	merged := p
+	if override.Resolution != 0 {
+		merged.Resolution = override.Resolution
+	}
+	if override.Mode != "" {
+		merged.Mode = override.Mode
+	}
+	if override.Source != "" {
+		merged.Source = override.Source
+	}
+	if override.Format != "" {
+		merged.Format = override.Format
+	}
+	if override.PostProcess != nil {
+		merged.PostProcess = override.PostProcess
+	}
+	if override.BlankThreshold != 0 {
+		merged.BlankThreshold = override.BlankThreshold
+	}
+	if override.OCR {
+		merged.OCR = true
+	}
+	if override.G4 {
+		merged.G4 = true
+	}
+	return merged
+}
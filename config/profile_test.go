@@ -0,0 +1,38 @@
+package config
+
+import "testing"
+
+func TestProfileMergeOverridesWin(t *testing.T) {
+	base := Profile{Resolution: 300, Mode: "gray", Format: "png"}
+	override := Profile{Resolution: 600, OCR: true, G4: true}
+
+	merged := base.Merge(override)
+
+	if merged.Resolution != 600 {
+		t.Errorf("Resolution = %d, want 600", merged.Resolution)
+	}
+	if merged.Mode != "gray" {
+		t.Errorf("Mode = %q, want base value to survive an empty override, got %q", merged.Mode, merged.Mode)
+	}
+	if merged.Format != "png" {
+		t.Errorf("Format = %q, want base value %q to survive an empty override", merged.Format, "png")
+	}
+	if !merged.OCR {
+		t.Error("OCR = false, want true from override")
+	}
+	if !merged.G4 {
+		t.Error("G4 = false, want true from override")
+	}
+}
+
+func TestProfileMergeZeroOverrideLeavesBase(t *testing.T) {
+	base := Profile{Resolution: 300, BlankThreshold: 5}
+	merged := base.Merge(Profile{})
+
+	if merged.Resolution != 300 {
+		t.Errorf("Resolution = %d, want base value 300 to survive a zero-value override", merged.Resolution)
+	}
+	if merged.BlankThreshold != 5 {
+		t.Errorf("BlankThreshold = %v, want base value 5 to survive a zero-value override", merged.BlankThreshold)
+	}
+}
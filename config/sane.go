@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/tjgq/sane"
+)
+
+// Apply sets the SANE options p specifies (resolution, mode, source) on
+// c, looking each one up in opts to resolve its real SANE name the way
+// main.go's parseOptions does. A zero-value field is left untouched
+// rather than cleared, so a profile only overrides what it actually
+// sets; the caller is responsible for applying any further per-request
+// overrides afterwards. It's shared by the HTTP and eSCL servers so a
+// resolved profile is applied identically from either.
+func (p Profile) Apply(c *sane.Conn, opts []sane.Option) error {
+	if p.Resolution != 0 {
+		so, err := findOption(opts, "resolution")
+		if err != nil {
+			return err
+		}
+		if _, err := c.SetOption(so.Name, p.Resolution); err != nil {
+			return fmt.Errorf("apply profile: set resolution: %w", err)
+		}
+	}
+	if p.Mode != "" {
+		so, err := findOption(opts, "mode")
+		if err != nil {
+			return err
+		}
+		if _, err := c.SetOption(so.Name, p.Mode); err != nil {
+			return fmt.Errorf("apply profile: set mode: %w", err)
+		}
+	}
+	if src := sourceOptionString(p.Source); src != "" {
+		so, err := findOption(opts, "source")
+		if err != nil {
+			return err
+		}
+		if _, err := c.SetOption(so.Name, src); err != nil {
+			return fmt.Errorf("apply profile: set source: %w", err)
+		}
+	}
+	return nil
+}
+
+// sourceOptionString maps a profile's source name to the SANE option
+// string, or "" when source is unset, so Apply knows not to touch the
+// device's current source. main.go's saneSourceString is a separate,
+// always-resolving version of this mapping for the CLI, which must pick
+// some source even when a job spec leaves it unset.
+func sourceOptionString(source string) string {
+	switch source {
+	case "adf":
+		return "ADF"
+	case "adf-duplex":
+		return "ADF Duplex"
+	case "flatbed":
+		return "Flatbed"
+	default:
+		return ""
+	}
+}
+
+// findOption looks up name's real SANE option, mirroring main.go's
+// findOption and server.findSaneOption.
+func findOption(opts []sane.Option, name string) (*sane.Option, error) {
+	for _, o := range opts {
+		if o.Name == name {
+			return &o, nil
+		}
+	}
+	return nil, fmt.Errorf("no such option %s", name)
+}
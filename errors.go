@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// ScanError wraps a failure from the SANE layer (or this package) with
+// the operation that triggered it, so callers can both log a useful
+// message and errors.Is/As against a stable sentinel.
+type ScanError struct {
+	Op  string
+	Err error
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// ErrOptionNotFound is returned when a requested SANE option does
+	// not exist on the device.
+	ErrOptionNotFound = fmt.Errorf("option not found")
+	// ErrDeviceBusy marks a device that is already in use by another
+	// scan. No function in this package returns it; the HTTP server
+	// waits on a per-device lock instead of failing.
+	ErrDeviceBusy = fmt.Errorf("device busy")
+	// ErrEncodeFailed is returned when the scanned image could not be
+	// encoded to the requested output format.
+	ErrEncodeFailed = fmt.Errorf("encode failed")
+)
+
+// Logger is the structured logging interface used throughout this
+// package, so the HTTP and job subsystems can emit JSON logs with
+// device/job correlation IDs instead of the process crashing or
+// writing unstructured text to stderr.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// slogLogger adapts *slog.Logger to the Logger interface.
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// NewDefaultLogger returns a Logger that writes structured JSON to
+// stderr, suitable when the caller hasn't configured anything else.
+func NewDefaultLogger() Logger {
+	return &slogLogger{l: slog.New(slog.NewJSONHandler(os.Stderr, nil))}
+}
+
+func (s *slogLogger) Debug(msg string, args ...any) {
+	s.l.Log(context.Background(), slog.LevelDebug, msg, args...)
+}
+func (s *slogLogger) Info(msg string, args ...any) {
+	s.l.Log(context.Background(), slog.LevelInfo, msg, args...)
+}
+func (s *slogLogger) Warn(msg string, args ...any) {
+	s.l.Log(context.Background(), slog.LevelWarn, msg, args...)
+}
+func (s *slogLogger) Error(msg string, args ...any) {
+	s.l.Log(context.Background(), slog.LevelError, msg, args...)
+}
@@ -0,0 +1,120 @@
+// Package escl implements enough of the Apple/Mopria eSCL ("AirScan")
+// protocol for this binary to appear as a driverless network scanner to
+// macOS, iOS and Android clients, the same way sane-airscan does for
+// SANE backends.
+package escl
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/tjgq/sane"
+)
+
+// ScannerCapabilities is the subset of the eSCL capabilities document
+// that clients actually read: device info plus the resolutions, color
+// modes and document formats it supports.
+type ScannerCapabilities struct {
+	XMLName      xml.Name         `xml:"scan:ScannerCapabilities"`
+	XmlnsPWG     string           `xml:"xmlns:pwg,attr"`
+	XmlnsScan    string           `xml:"xmlns:scan,attr"`
+	Version      string           `xml:"pwg:Version"`
+	MakeAndModel string           `xml:"pwg:MakeAndModel"`
+	SerialNumber string           `xml:"pwg:SerialNumber"`
+	Platen       *InputSourceCaps `xml:"scan:Platen"`
+	Adf          *InputSourceCaps `xml:"scan:Adf,omitempty"`
+}
+
+type InputSourceCaps struct {
+	InputSourceCaps InputCaps `xml:"scan:PlatenInputCaps"`
+}
+
+type InputCaps struct {
+	MinWidth        int              `xml:"scan:MinWidth"`
+	MaxWidth        int              `xml:"scan:MaxWidth"`
+	MinHeight       int              `xml:"scan:MinHeight"`
+	MaxHeight       int              `xml:"scan:MaxHeight"`
+	SettingProfiles []SettingProfile `xml:"scan:SettingProfiles>scan:SettingProfile"`
+}
+
+type SettingProfile struct {
+	ColorModes      []string `xml:"scan:ColorModes>scan:ColorMode"`
+	DocumentFormats []string `xml:"scan:DocumentFormats>scan:DocumentFormat"`
+	XResolutions    []int    `xml:"scan:SupportedResolutions>scan:DiscreteResolutions>scan:DiscreteResolution>scan:XResolution"`
+	YResolutions    []int    `xml:"scan:SupportedResolutions>scan:DiscreteResolutions>scan:DiscreteResolution>scan:YResolution"`
+}
+
+// capabilitiesFor synthesizes a ScannerCapabilities document from the
+// option list SANE reports for the open device, so eSCL clients see the
+// scanner's real resolutions rather than a fixed set. The color modes
+// and document formats listed are the same for every device.
+func capabilitiesFor(c *sane.Conn) *ScannerCapabilities {
+	profile := SettingProfile{
+		ColorModes:      []string{"BlackAndWhite1", "Grayscale8", "RGB24"},
+		DocumentFormats: []string{"image/jpeg", "application/pdf"},
+	}
+
+	for _, o := range c.Options() {
+		switch o.Name {
+		case "resolution":
+			if o.ConstrRange != nil {
+				max := intFromConstr(o.ConstrRange.Max)
+				profile.XResolutions = append(profile.XResolutions, max)
+				profile.YResolutions = append(profile.YResolutions, max)
+			}
+			for _, v := range o.ConstrSet {
+				if r, ok := v.(int); ok {
+					profile.XResolutions = append(profile.XResolutions, r)
+					profile.YResolutions = append(profile.YResolutions, r)
+				}
+			}
+		}
+	}
+	if len(profile.XResolutions) == 0 {
+		profile.XResolutions = []int{300}
+		profile.YResolutions = []int{300}
+	}
+
+	return &ScannerCapabilities{
+		XmlnsPWG:     "http://www.pwg.org/schemas/2010/12/sm",
+		XmlnsScan:    "http://schemas.hp.com/imaging/escl/2011/05/03",
+		Version:      "2.63",
+		MakeAndModel: c.Device,
+		SerialNumber: c.Device,
+		Platen: &InputSourceCaps{
+			InputCaps{
+				MaxWidth:        2550,
+				MaxHeight:       3300,
+				SettingProfiles: []SettingProfile{profile},
+			},
+		},
+	}
+}
+
+// intFromConstr converts a sane.Range bound, typed interface{} because
+// the underlying SANE option can be either integer or fixed-point, to
+// an int, rounding a float bound down.
+func intFromConstr(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
+
+// HandleCapabilities serves GET /eSCL/ScannerCapabilities.
+func (s *Server) HandleCapabilities(w http.ResponseWriter, r *http.Request) {
+	c, err := sane.Open(s.device)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer c.Close()
+
+	w.Header().Set("Content-Type", "text/xml")
+	w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(capabilitiesFor(c))
+}
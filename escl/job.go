@@ -0,0 +1,116 @@
+package escl
+
+import (
+	"crypto/rand"
+	"fmt"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/tjgq/sane"
+)
+
+// scanJob is the eSCL ScanJob created by POST /eSCL/ScanJobs. Pages
+// are read lazily: each GET .../NextDocument pulls the next image
+// straight off the scanner rather than pre-reading the whole batch. mu
+// guards conn/done, since sane.Conn isn't safe for concurrent
+// ReadImage calls and two NextDocument requests for the same job could
+// otherwise race.
+type scanJob struct {
+	mu   sync.Mutex
+	conn *sane.Conn
+	done bool
+}
+
+// handleCreateJob implements POST /eSCL/ScanJobs: it opens the device,
+// leaves it ready to read pages, and returns the job's URI in the
+// Location header the way a physical eSCL scanner does.
+func (s *Server) handleCreateJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	c, err := sane.Open(s.device)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if s.cfg != nil {
+		if profile, ok := s.cfg.ProfileForDevice(s.device); ok {
+			if err := profile.Apply(c, c.Options()); err != nil {
+				c.Close()
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	uuid, err := newUUID()
+	if err != nil {
+		c.Close()
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	s.jobs[uuid] = &scanJob{conn: c}
+	s.mu.Unlock()
+
+	w.Header().Set("Location", "/eSCL/ScanJobs/"+uuid)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// handleNextDocument implements GET /eSCL/ScanJobs/{uuid}/NextDocument:
+// it reads one page from the job's SANE connection and streams it back
+// as a JPEG, returning 404 once the job has no more pages.
+func (s *Server) handleNextDocument(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/eSCL/ScanJobs/")
+	uuid := strings.TrimSuffix(rest, "/NextDocument")
+
+	s.mu.Lock()
+	job, ok := s.jobs[uuid]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+
+	if job.done {
+		http.Error(w, "no more documents", http.StatusNotFound)
+		return
+	}
+
+	img, err := job.conn.ReadImage()
+	if err == io.EOF {
+		job.done = true
+		job.conn.Close()
+		http.Error(w, "no more documents", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		job.done = true
+		job.conn.Close()
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	if err := jpeg.Encode(w, img, nil); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,70 @@
+package escl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+)
+
+func TestNewUUIDFormat(t *testing.T) {
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		uuid, err := newUUID()
+		if err != nil {
+			t.Fatalf("newUUID() error = %v", err)
+		}
+		if !re.MatchString(uuid) {
+			t.Fatalf("newUUID() = %q, want a dash-grouped hex uuid", uuid)
+		}
+		if seen[uuid] {
+			t.Fatalf("newUUID() returned duplicate %q", uuid)
+		}
+		seen[uuid] = true
+	}
+}
+
+func TestHandleNextDocumentUnknownJob(t *testing.T) {
+	s := &Server{jobs: make(map[string]*scanJob)}
+
+	req := httptest.NewRequest(http.MethodGet, "/eSCL/ScanJobs/missing/NextDocument", nil)
+	rec := httptest.NewRecorder()
+	s.handleNextDocument(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+// TestHandleNextDocumentConcurrentRequestsSerialize exercises scanJob.mu,
+// the lock that keeps two NextDocument requests for the same job from
+// racing on job.done. A finished job never reaches job.conn, so this
+// drives the locking itself without needing a real SANE connection; run
+// with -race to catch a regression back to the unlocked version.
+func TestHandleNextDocumentConcurrentRequestsSerialize(t *testing.T) {
+	s := &Server{jobs: map[string]*scanJob{"job1": {done: true}}}
+
+	const n = 50
+	codes := make([]int, n)
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, "/eSCL/ScanJobs/job1/NextDocument", nil)
+			rec := httptest.NewRecorder()
+			s.handleNextDocument(rec, req)
+			codes[i] = rec.Code
+		}(i)
+	}
+	wg.Wait()
+
+	for i, code := range codes {
+		if code != http.StatusNotFound {
+			t.Errorf("request %d: status = %d, want %d", i, code, http.StatusNotFound)
+		}
+	}
+}
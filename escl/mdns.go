@@ -0,0 +1,33 @@
+package escl
+
+import (
+	"fmt"
+
+	"github.com/grandcat/zeroconf"
+)
+
+// Advertise publishes this Server on the local network as an
+// _uscan._tcp (or _uscans._tcp for TLS) service, the mDNS record Apple,
+// Mopria and most scan apps look for before falling back to a manual
+// IP. Callers should keep the returned zeroconf.Server alive for as
+// long as the scanner should stay discoverable, and call Shutdown when
+// done.
+func Advertise(name string, port int, tls bool) (*zeroconf.Server, error) {
+	service := "_uscan._tcp"
+	if tls {
+		service = "_uscans._tcp"
+	}
+
+	txt := []string{
+		"txtvers=1",
+		"rs=eSCL",
+		"pdl=application/pdf,image/jpeg",
+		"ty=" + name,
+	}
+
+	srv, err := zeroconf.Register(name, service, "local.", port, txt, nil)
+	if err != nil {
+		return nil, fmt.Errorf("advertise %s: %w", service, err)
+	}
+	return srv, nil
+}
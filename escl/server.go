@@ -0,0 +1,40 @@
+package escl
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/vit1251/scan-server/config"
+)
+
+// Server adapts a single SANE device to the eSCL HTTP surface. One
+// Server is mounted per device; the mDNS advertisement (see mdns.go)
+// lets clients discover it without being told an address up front. The
+// eSCL protocol gives clients no way to pick a profile by name (a
+// ScanSettings body isn't parsed here), so cfg, if set, only supplies
+// the device's [device.<pattern>] default profile, applied to every job.
+type Server struct {
+	device string // SANE device name this Server scans from
+	cfg    *config.Config
+
+	mu   sync.Mutex
+	jobs map[string]*scanJob
+}
+
+// New returns a Server that scans from the named SANE device. cfg may
+// be nil, in which case jobs use whatever options the device already
+// has set.
+func New(device string, cfg *config.Config) *Server {
+	return &Server{
+		device: device,
+		cfg:    cfg,
+		jobs:   make(map[string]*scanJob),
+	}
+}
+
+// Routes registers the eSCL endpoints on mux, rooted at /eSCL.
+func (s *Server) Routes(mux *http.ServeMux) {
+	mux.HandleFunc("/eSCL/ScannerCapabilities", s.HandleCapabilities)
+	mux.HandleFunc("/eSCL/ScanJobs", s.handleCreateJob)
+	mux.HandleFunc("/eSCL/ScanJobs/", s.handleNextDocument)
+}
@@ -0,0 +1,85 @@
+package imgproc
+
+import (
+	"image"
+	"image/color"
+)
+
+// Binarize converts img to black/white using Otsu's method to pick the
+// threshold automatically, rather than a fixed cutoff that only works
+// for one lighting/paper combination.
+func Binarize(img image.Image) (image.Image, error) {
+	b := img.Bounds()
+	var histogram [256]int
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			histogram[luma(img, x, y)]++
+		}
+	}
+
+	threshold := otsuThreshold(histogram, b.Dx()*b.Dy())
+
+	out := image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			v := uint8(0)
+			if luma(img, x, y) > uint32(threshold) {
+				v = 255
+			}
+			out.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return out, nil
+}
+
+// otsuThreshold picks the luma cut point that maximizes the variance
+// between the two classes it splits the histogram into. A flat gap
+// between the ink and paper clusters (the common case for a clean scan)
+// makes every threshold inside the gap tie for the same maximal
+// variance, so rather than keeping whichever tied threshold is found
+// first -- the low edge of the gap, right against the dark cluster --
+// this averages every tied threshold to land in the middle of the gap.
+func otsuThreshold(histogram [256]int, total int) int {
+	sumAll := 0
+	for i, c := range histogram {
+		sumAll += i * c
+	}
+
+	variances := make([]float64, 256)
+	var bestVariance float64
+	sumB, wB := 0, 0
+
+	for t := 0; t < 256; t++ {
+		wB += histogram[t]
+		if wB == 0 {
+			continue
+		}
+		wF := total - wB
+		if wF == 0 {
+			break
+		}
+
+		sumB += t * histogram[t]
+		meanB := float64(sumB) / float64(wB)
+		meanF := float64(sumAll-sumB) / float64(wF)
+
+		variance := float64(wB) * float64(wF) * (meanB - meanF) * (meanB - meanF)
+		variances[t] = variance
+		if variance > bestVariance {
+			bestVariance = variance
+		}
+	}
+
+	sumT, n := 0, 0
+	for t, v := range variances {
+		if v == bestVariance {
+			sumT += t
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sumT / n
+}
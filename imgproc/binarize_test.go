@@ -0,0 +1,43 @@
+package imgproc
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestOtsuThreshold(t *testing.T) {
+	var histogram [256]int
+	// Two well-separated clusters: a dark cluster near 20 and a light
+	// cluster near 230, as a scanned page's ink/paper split would look.
+	histogram[20] = 100
+	histogram[230] = 100
+
+	got := otsuThreshold(histogram, 200)
+	if got <= 20 || got >= 230 {
+		t.Fatalf("otsuThreshold() = %d, want a cut point between the two clusters", got)
+	}
+}
+
+func TestBinarize(t *testing.T) {
+	b := image.Rect(0, 0, 2, 1)
+	src := image.NewGray(b)
+	src.SetGray(0, 0, color.Gray{Y: 10})  // ink
+	src.SetGray(1, 0, color.Gray{Y: 245}) // paper
+
+	out, err := Binarize(src)
+	if err != nil {
+		t.Fatalf("Binarize() error = %v", err)
+	}
+
+	gray, ok := out.(*image.Gray)
+	if !ok {
+		t.Fatalf("Binarize() returned %T, want *image.Gray", out)
+	}
+	if gray.GrayAt(0, 0).Y != 0 {
+		t.Errorf("ink pixel binarized to %d, want 0", gray.GrayAt(0, 0).Y)
+	}
+	if gray.GrayAt(1, 0).Y != 255 {
+		t.Errorf("paper pixel binarized to %d, want 255", gray.GrayAt(1, 0).Y)
+	}
+}
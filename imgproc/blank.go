@@ -0,0 +1,37 @@
+package imgproc
+
+import (
+	"image"
+	"math"
+)
+
+// IsBlank reports whether img is a blank page: one whose luma has
+// almost no spread, meaning it's uniform background rather than
+// scanned content. A page is dropped when its standard deviation falls
+// below threshold.
+func IsBlank(img image.Image, threshold float64) bool {
+	b := img.Bounds()
+	n := 0
+	sum, sumSq := 0.0, 0.0
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			v := float64(luma(img, x, y))
+			sum += v
+			sumSq += v * v
+			n++
+		}
+	}
+	if n == 0 {
+		return true
+	}
+
+	mean := sum / float64(n)
+	variance := sumSq/float64(n) - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+	stddev := math.Sqrt(variance)
+
+	return stddev < threshold
+}
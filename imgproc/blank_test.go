@@ -0,0 +1,37 @@
+package imgproc
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestIsBlankUniformPage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.SetGray(x, y, color.Gray{Y: 250})
+		}
+	}
+
+	if !IsBlank(img, 10) {
+		t.Error("IsBlank() = false for a uniform page, want true")
+	}
+}
+
+func TestIsBlankPageWithContent(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			v := uint8(250)
+			if x < 4 {
+				v = 10
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+
+	if IsBlank(img, 10) {
+		t.Error("IsBlank() = true for a half-ink page, want false")
+	}
+}
@@ -0,0 +1,58 @@
+package imgproc
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// EncodeCCITTG4TIFF writes img to w as a CCITT Group 4 compressed
+// bitonal TIFF. Go's standard library (and golang.org/x/image/tiff)
+// can't produce G4 output, so this binarizes the page and shells out
+// to ImageMagick's convert, which every machine set up for scanning
+// already has installed alongside SANE, via a temporary output file
+// that gets copied to w.
+func EncodeCCITTG4TIFF(w io.Writer, img image.Image) error {
+	bin, err := Binarize(img)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.CreateTemp("", "scan-*.png")
+	if err != nil {
+		return fmt.Errorf("ccitt: %w", err)
+	}
+	defer os.Remove(in.Name())
+
+	if err := png.Encode(in, bin); err != nil {
+		in.Close()
+		return fmt.Errorf("ccitt: encode intermediate png: %w", err)
+	}
+	if err := in.Close(); err != nil {
+		return fmt.Errorf("ccitt: %w", err)
+	}
+
+	out, err := os.CreateTemp("", "scan-*.tiff")
+	if err != nil {
+		return fmt.Errorf("ccitt: %w", err)
+	}
+	defer os.Remove(out.Name())
+	out.Close()
+
+	cmd := exec.Command("convert", in.Name(), "-compress", "Group4", "-monochrome", out.Name())
+	if cmbd, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ccitt: convert: %w: %s", err, cmbd)
+	}
+
+	tiffData, err := os.Open(out.Name())
+	if err != nil {
+		return fmt.Errorf("ccitt: %w", err)
+	}
+	defer tiffData.Close()
+
+	_, err = io.Copy(w, tiffData)
+	return err
+}
@@ -0,0 +1,68 @@
+package imgproc
+
+import "image"
+
+// backgroundThreshold is the lowest 8-bit luma at which a pixel still
+// counts as page background for AutoCrop's bounding-box search.
+const backgroundThreshold = 250
+
+// AutoCrop trims the uniform white border SANE flatbeds tend to include
+// around the document, returning the bounding box of everything that
+// isn't background.
+func AutoCrop(img image.Image) (image.Image, error) {
+	b := img.Bounds()
+	minX, minY, maxX, maxY := b.Max.X, b.Max.Y, b.Min.X, b.Min.Y
+	found := false
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if luma(img, x, y) >= backgroundThreshold {
+				continue
+			}
+			found = true
+			if x < minX {
+				minX = x
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if y < minY {
+				minY = y
+			}
+			if y > maxY {
+				maxY = y
+			}
+		}
+	}
+
+	if !found {
+		return img, nil
+	}
+
+	crop := image.Rect(minX, minY, maxX+1, maxY+1)
+	return subImage(img, crop), nil
+}
+
+// subImage extracts crop from img without assuming img supports the
+// SubImage method that the concrete image types provide.
+func subImage(img image.Image, crop image.Rectangle) image.Image {
+	if si, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return si.SubImage(crop)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, crop.Dx(), crop.Dy()))
+	for y := crop.Min.Y; y < crop.Max.Y; y++ {
+		for x := crop.Min.X; x < crop.Max.X; x++ {
+			dst.Set(x-crop.Min.X, y-crop.Min.Y, img.At(x, y))
+		}
+	}
+	return dst
+}
+
+// luma returns the 8-bit perceptual brightness of a pixel.
+func luma(img image.Image, x, y int) uint32 {
+	r, g, b, _ := img.At(x, y).RGBA()
+	return (299*(r>>8) + 587*(g>>8) + 114*(b>>8)) / 1000
+}
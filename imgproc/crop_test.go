@@ -0,0 +1,49 @@
+package imgproc
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestAutoCrop(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	// A single block of content away from the edges.
+	for y := 3; y <= 5; y++ {
+		for x := 2; x <= 4; x++ {
+			img.Set(x, y, color.Black)
+		}
+	}
+
+	out, err := AutoCrop(img)
+	if err != nil {
+		t.Fatalf("AutoCrop() error = %v", err)
+	}
+
+	b := out.Bounds()
+	if b.Dx() != 3 || b.Dy() != 3 {
+		t.Errorf("AutoCrop() bounds = %v, want a 3x3 box", b)
+	}
+}
+
+func TestAutoCropBlankPage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+
+	out, err := AutoCrop(img)
+	if err != nil {
+		t.Fatalf("AutoCrop() error = %v", err)
+	}
+	if out.Bounds() != img.Bounds() {
+		t.Errorf("AutoCrop() of a blank page = %v, want unchanged bounds %v", out.Bounds(), img.Bounds())
+	}
+}
@@ -0,0 +1,102 @@
+package imgproc
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// edgeThreshold marks a pixel as an edge/ink pixel for the purposes of
+// skew detection.
+const edgeThreshold = 200
+
+// angleRange and angleStep bound the Hough search: printed pages are
+// rarely skewed by more than a few degrees, and anything beyond that
+// is more likely a misfed page than a rotation to correct for.
+const (
+	angleRange = 5.0 // +/- degrees
+	angleStep  = 0.2
+)
+
+// Deskew estimates the page's rotation with a Hough transform over its
+// dark pixels and rotates the image back to level.
+func Deskew(img image.Image) (image.Image, error) {
+	angle := detectSkewAngle(img)
+	if angle == 0 {
+		return img, nil
+	}
+	return rotate(img, -angle), nil
+}
+
+// detectSkewAngle runs a Hough accumulator over the candidate angle
+// range and returns the angle (in degrees) whose rho histogram has the
+// tallest peak, i.e. the angle at which the most dark pixels line up on
+// a common line -- text baselines, table rules, the page edge.
+func detectSkewAngle(img image.Image) float64 {
+	b := img.Bounds()
+
+	bestAngle := 0.0
+	bestVotes := 0
+
+	for angle := -angleRange; angle <= angleRange; angle += angleStep {
+		theta := angle * math.Pi / 180
+		cos, sin := math.Cos(theta), math.Sin(theta)
+
+		votes := make(map[int]int)
+		for y := b.Min.Y; y < b.Max.Y; y += 4 { // subsample for speed
+			for x := b.Min.X; x < b.Max.X; x += 4 {
+				if luma(img, x, y) >= edgeThreshold {
+					continue
+				}
+				rho := int(-float64(x)*sin + float64(y)*cos)
+				votes[rho]++
+			}
+		}
+
+		peak := 0
+		for _, v := range votes {
+			if v > peak {
+				peak = v
+			}
+		}
+		// A perfectly level line votes for the same peak at several
+		// angles (rho's int() truncation maps nearby angles to the same
+		// bucket), so on a tie prefer the angle closest to 0 rather than
+		// whichever angle the sweep happens to reach first.
+		if peak > bestVotes || (peak == bestVotes && math.Abs(angle) < math.Abs(bestAngle)) {
+			bestVotes = peak
+			bestAngle = angle
+		}
+	}
+
+	return bestAngle
+}
+
+// rotate rotates img by angle degrees around its center, using nearest
+// neighbor sampling and filling uncovered corners with white.
+func rotate(img image.Image, angle float64) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	theta := angle * math.Pi / 180
+	cos, sin := math.Cos(theta), math.Sin(theta)
+	cx, cy := float64(w)/2, float64(h)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			// Map the destination pixel back to a source coordinate by
+			// rotating around the center in the opposite direction.
+			dx, dy := float64(x)-cx, float64(y)-cy
+			sx := dx*cos + dy*sin + cx
+			sy := -dx*sin + dy*cos + cy
+
+			ix, iy := int(sx)+b.Min.X, int(sy)+b.Min.Y
+			if ix < b.Min.X || ix >= b.Max.X || iy < b.Min.Y || iy >= b.Max.Y {
+				dst.Set(x, y, color.White)
+				continue
+			}
+			dst.Set(x, y, img.At(ix, iy))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,45 @@
+package imgproc
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestDeskewBlankPageUnchanged(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 20, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+
+	out, err := Deskew(img)
+	if err != nil {
+		t.Fatalf("Deskew() error = %v", err)
+	}
+	if out != image.Image(img) {
+		t.Error("Deskew() rotated a blank page with no detectable skew, want it returned unchanged")
+	}
+}
+
+func TestDetectSkewAngleHorizontalLine(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+	// A flat horizontal line of ink votes hardest for angle 0.
+	for x := 0; x < 40; x++ {
+		img.SetGray(x, 20, color.Gray{Y: 0})
+	}
+
+	// The angle sweep accumulates its step by repeated float addition,
+	// so the bucket nearest 0 can land a hair off it; tolerate that
+	// instead of requiring bit-exact equality.
+	if got := detectSkewAngle(img); math.Abs(got) > 1e-9 {
+		t.Errorf("detectSkewAngle() of a flat horizontal line = %v, want ~0", got)
+	}
+}
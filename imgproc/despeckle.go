@@ -0,0 +1,50 @@
+package imgproc
+
+import (
+	"image"
+	"image/color"
+)
+
+// speckleThreshold is the 8-bit luma below which a pixel is considered
+// ink rather than paper.
+const speckleThreshold = 200
+
+// Despeckle removes isolated single-pixel noise -- the salt-and-pepper
+// dust flatbed scans pick up -- by replacing any dark pixel whose 8
+// neighbors are all background with white, leaving real strokes
+// (which have at least one dark neighbor) untouched.
+func Despeckle(img image.Image) (image.Image, error) {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if luma(img, x, y) < speckleThreshold && isIsolated(img, b, x, y) {
+				out.Set(x, y, color.White)
+				continue
+			}
+			out.Set(x, y, img.At(x, y))
+		}
+	}
+	return out, nil
+}
+
+// isIsolated reports whether every one of (x, y)'s 8 neighbors inside b
+// is background, i.e. (x, y) has no adjacent ink pixel.
+func isIsolated(img image.Image, b image.Rectangle, x, y int) bool {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			nx, ny := x+dx, y+dy
+			if nx < b.Min.X || nx >= b.Max.X || ny < b.Min.Y || ny >= b.Max.Y {
+				continue
+			}
+			if luma(img, nx, ny) < speckleThreshold {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package imgproc
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func whitePage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	return img
+}
+
+func TestDespeckleRemovesIsolatedDot(t *testing.T) {
+	img := whitePage(5, 5)
+	img.Set(2, 2, color.Black)
+
+	out, err := Despeckle(img)
+	if err != nil {
+		t.Fatalf("Despeckle() error = %v", err)
+	}
+	if luma(out, 2, 2) < speckleThreshold {
+		t.Error("Despeckle() left an isolated dot in place, want it removed")
+	}
+}
+
+func TestDespeckleKeepsAdjacentInk(t *testing.T) {
+	img := whitePage(5, 5)
+	img.Set(2, 2, color.Black)
+	img.Set(2, 3, color.Black)
+
+	out, err := Despeckle(img)
+	if err != nil {
+		t.Fatalf("Despeckle() error = %v", err)
+	}
+	if luma(out, 2, 2) >= speckleThreshold || luma(out, 2, 3) >= speckleThreshold {
+		t.Error("Despeckle() removed ink that had an adjacent dark pixel, want it kept")
+	}
+}
@@ -0,0 +1,29 @@
+package imgproc
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// hocrWordRe matches one hOCR ocrx_word span, e.g.:
+//
+//	<span class='ocrx_word' title='bbox 102 45 180 70; x_wconf 96'>Invoice</span>
+var hocrWordRe = regexp.MustCompile(`(?s)class='ocrx_word'[^>]*bbox (\d+) (\d+) (\d+) (\d+)[^>]*>([^<]*)<`)
+
+// parseHOCRWords extracts each recognized word and its bounding box
+// from a tesseract hOCR document.
+func parseHOCRWords(hocr []byte) []ocrWord {
+	var words []ocrWord
+	for _, m := range hocrWordRe.FindAllSubmatch(hocr, -1) {
+		x0, _ := strconv.Atoi(string(m[1]))
+		y0, _ := strconv.Atoi(string(m[2]))
+		x1, _ := strconv.Atoi(string(m[3]))
+		y1, _ := strconv.Atoi(string(m[4]))
+		text := string(m[5])
+		if text == "" {
+			continue
+		}
+		words = append(words, ocrWord{Text: text, X: x0, Y: y0, W: x1 - x0, H: y1 - y0})
+	}
+	return words
+}
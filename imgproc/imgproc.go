@@ -0,0 +1,52 @@
+// Package imgproc implements the post-scan image pipeline: auto-crop,
+// deskew, blank-page detection, monochrome conversion and searchable
+// PDF output. It runs after c.ReadImage() in doScan/runBatchScan, driven
+// by the step names a JobSpec lists in PostProcess.
+package imgproc
+
+import (
+	"fmt"
+	"image"
+)
+
+// Filter transforms one scanned page. A Filter may return a smaller or
+// differently-typed image (e.g. Binarize returns *image.Gray).
+type Filter func(image.Image) (image.Image, error)
+
+// Pipeline is an ordered list of named filters plus the blank-page
+// threshold, built from a JobSpec's PostProcess list.
+type Pipeline struct {
+	Steps          []string
+	BlankThreshold float64 // stddev below which a page is dropped; 0 disables the check
+}
+
+// filters maps the step names accepted in a job spec's PostProcess list
+// to the Filter that implements them.
+var filters = map[string]Filter{
+	"crop":      AutoCrop,
+	"deskew":    Deskew,
+	"despeckle": Despeckle,
+	"binarize":  Binarize,
+}
+
+// Apply runs img through each configured step in order. It returns
+// keep=false (with a nil image) when the blank-page check drops the
+// page, so callers know to skip writing it out.
+func (p Pipeline) Apply(img image.Image) (out image.Image, keep bool, err error) {
+	if p.BlankThreshold > 0 && IsBlank(img, p.BlankThreshold) {
+		return nil, false, nil
+	}
+
+	out = img
+	for _, name := range p.Steps {
+		f, ok := filters[name]
+		if !ok {
+			return nil, false, fmt.Errorf("imgproc: unknown post-process step %q", name)
+		}
+		out, err = f(out)
+		if err != nil {
+			return nil, false, fmt.Errorf("imgproc: step %q: %w", name, err)
+		}
+	}
+	return out, true, nil
+}
@@ -0,0 +1,55 @@
+package imgproc
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPipelineApplyUnknownStep(t *testing.T) {
+	p := Pipeline{Steps: []string{"sharpen"}}
+	img := image.NewGray(image.Rect(0, 0, 2, 2))
+
+	if _, _, err := p.Apply(img); err == nil {
+		t.Error("Apply() with an unknown step returned nil error, want one")
+	}
+}
+
+func TestPipelineApplyDropsBlankPage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.SetGray(x, y, color.Gray{Y: 250})
+		}
+	}
+
+	p := Pipeline{BlankThreshold: 10}
+	out, keep, err := p.Apply(img)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if keep || out != nil {
+		t.Errorf("Apply() of a blank page = (%v, %v), want (nil, false)", out, keep)
+	}
+}
+
+func TestPipelineApplyRunsConfiguredStep(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.SetGray(x, y, color.Gray{Y: 250})
+		}
+	}
+
+	p := Pipeline{Steps: []string{"binarize"}}
+	out, keep, err := p.Apply(img)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if !keep {
+		t.Fatal("Apply() dropped a non-blank page")
+	}
+	if _, ok := out.(*image.Gray); !ok {
+		t.Errorf("Apply() result is %T, want *image.Gray from binarize", out)
+	}
+}
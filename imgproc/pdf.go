@@ -0,0 +1,75 @@
+package imgproc
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// EncodeSearchablePDF writes img as a single-page PDF to w. When ocr is
+// true it first runs tesseract to recognize the page's text and embeds
+// the recognized words as an invisible text layer positioned over the
+// image, the same trick scanner vendors' "searchable PDF" mode uses, so
+// the page looks like the scan but can be selected/searched/copied.
+func EncodeSearchablePDF(w io.Writer, img image.Image, ocr bool) error {
+	var jpegBuf bytes.Buffer
+	if err := jpeg.Encode(&jpegBuf, img, &jpeg.Options{Quality: 90}); err != nil {
+		return fmt.Errorf("pdf: encode jpeg: %w", err)
+	}
+
+	var words []ocrWord
+	if ocr {
+		var err error
+		words, err = recognizeText(jpegBuf.Bytes())
+		if err != nil {
+			return fmt.Errorf("pdf: ocr: %w", err)
+		}
+	}
+
+	b := img.Bounds()
+	pdf := buildSinglePagePDF(jpegBuf.Bytes(), b.Dx(), b.Dy(), words)
+
+	_, err := w.Write(pdf)
+	return err
+}
+
+// ocrWord is one recognized word placed at its bounding box, in pixel
+// coordinates with the origin at the page's top-left.
+type ocrWord struct {
+	Text       string
+	X, Y, W, H int
+}
+
+// recognizeText runs tesseract in hOCR mode and parses out each word's
+// text and bounding box (tesseract's `bbox` properties), so the caller
+// doesn't need to link against an OCR engine directly.
+func recognizeText(jpegData []byte) ([]ocrWord, error) {
+	tmp, err := os.CreateTemp("", "scan-*.jpg")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(jpegData); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	tmp.Close()
+
+	outBase := tmp.Name() + "-hocr"
+	defer os.Remove(outBase + ".hocr")
+
+	cmd := exec.Command("tesseract", tmp.Name(), outBase, "hocr")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("tesseract: %w: %s", err, out)
+	}
+
+	hocr, err := os.ReadFile(outBase + ".hocr")
+	if err != nil {
+		return nil, err
+	}
+	return parseHOCRWords(hocr), nil
+}
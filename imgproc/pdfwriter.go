@@ -0,0 +1,81 @@
+package imgproc
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// buildSinglePagePDF hand-assembles a minimal one-page PDF: the scanned
+// image as a DCTDecode (JPEG) XObject filling the page, plus one
+// invisible (text rendering mode 3) string per recognized word,
+// positioned over where tesseract found it. No PDF library is pulled
+// in for what's otherwise a few fixed objects.
+func buildSinglePagePDF(jpegData []byte, width, height int, words []ocrWord) []byte {
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "q %d 0 0 %d 0 0 cm /Im0 Do Q\n", width, height)
+	if len(words) > 0 {
+		content.WriteString("BT /F1 12 Tf 3 Tr\n")
+		for _, w := range words {
+			// hOCR bboxes are top-left/y-down; PDF text origin is
+			// bottom-left/y-up, so flip and drop to the bbox's baseline.
+			x := w.X
+			y := height - w.Y - w.H
+			fmt.Fprintf(&content, "1 0 0 1 %d %d Tm (%s) Tj\n", x, y, escapePDFString(w.Text))
+		}
+		content.WriteString("ET\n")
+	}
+
+	var objs [][]byte
+	objs = append(objs, []byte("<< /Type /Catalog /Pages 2 0 R >>"))
+	objs = append(objs, []byte("<< /Type /Pages /Kids [3 0 R] /Count 1 >>"))
+	objs = append(objs, []byte(fmt.Sprintf(
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Contents 4 0 R "+
+			"/Resources << /XObject << /Im0 5 0 R >> /Font << /F1 6 0 R >> >> >>",
+		width, height)))
+	objs = append(objs, []byte(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String())))
+	objs = append(objs, []byte(fmt.Sprintf(
+		"<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB "+
+			"/BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream",
+		width, height, len(jpegData), jpegData)))
+	objs = append(objs, []byte("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"))
+
+	return assemblePDF(objs)
+}
+
+// assemblePDF wraps numbered object bodies in "N 0 obj ... endobj",
+// emits them after the %PDF header, and appends a cross-reference table
+// and trailer pointing at the catalog (object 1).
+func assemblePDF(objs [][]byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objs)+1)
+	for i, body := range objs {
+		offsets[i+1] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+
+	xrefStart := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(objs)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for i := 1; i <= len(objs); i++ {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offsets[i])
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF", len(objs)+1, xrefStart)
+
+	return buf.Bytes()
+}
+
+func escapePDFString(s string) string {
+	var out bytes.Buffer
+	for _, r := range s {
+		switch r {
+		case '(', ')', '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		default:
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
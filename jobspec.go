@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vit1251/scan-server/config"
+	"github.com/vit1251/scan-server/imgproc"
+	"gopkg.in/yaml.v3"
+)
+
+// JobSpec describes a batch scan request: source, resolution, mode, page
+// size, output format and post-processing. It replaces the hard-coded
+// options slice in doScan so a scripted batch of scans can be submitted
+// as a single YAML or JSON document instead of recompiling the binary.
+type JobSpec struct {
+	Source         string   `json:"source" yaml:"source"` // "flatbed", "adf", "adf-duplex"
+	Resolution     int      `json:"resolution" yaml:"resolution"`
+	Mode           string   `json:"mode" yaml:"mode"`                     // "color", "gray", "lineart"
+	PageSize       string   `json:"pageSize" yaml:"pageSize"`             // "a4", "letter", ...
+	Output         string   `json:"output" yaml:"output"`                 // "pages" or "multipage"
+	Format         string   `json:"format" yaml:"format"`                 // "png", "tiff", "pdf"
+	Dest           string   `json:"dest" yaml:"dest"`                     // output dir (pages) or file (multipage)
+	PostProcess    []string `json:"postProcess" yaml:"postProcess"`       // e.g. "crop", "deskew", "binarize"
+	BlankThreshold float64  `json:"blankThreshold" yaml:"blankThreshold"` // drop pages with luma stddev below this; 0 disables
+	OCR            bool     `json:"ocr" yaml:"ocr"`                       // embed a searchable text layer in .pdf output
+	G4             bool     `json:"g4" yaml:"g4"`                         // compress .tiff/.tif output as CCITT Group 4
+}
+
+// pipeline builds the imgproc.Pipeline this job's PostProcess and
+// BlankThreshold describe.
+func (j *JobSpec) pipeline() imgproc.Pipeline {
+	return imgproc.Pipeline{Steps: j.PostProcess, BlankThreshold: j.BlankThreshold}
+}
+
+// applyProfile fills in the job's scan parameters from a config
+// profile, with any value the job spec already set taking precedence
+// over the profile's default.
+func (j *JobSpec) applyProfile(p config.Profile) {
+	merged := p.Merge(config.Profile{
+		Resolution:     j.Resolution,
+		Mode:           j.Mode,
+		Source:         j.Source,
+		Format:         j.Format,
+		PostProcess:    j.PostProcess,
+		BlankThreshold: j.BlankThreshold,
+		OCR:            j.OCR,
+		G4:             j.G4,
+	})
+	j.Resolution = merged.Resolution
+	j.Mode = merged.Mode
+	j.Source = merged.Source
+	j.Format = merged.Format
+	j.PostProcess = merged.PostProcess
+	j.BlankThreshold = merged.BlankThreshold
+	j.OCR = merged.OCR
+	j.G4 = merged.G4
+}
+
+// LoadJobSpec reads a job spec from a .json or .yaml/.yml file.
+func LoadJobSpec(path string) (*JobSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read job spec: %w", err)
+	}
+
+	var spec JobSpec
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.Unmarshal(data, &spec); err != nil {
+			return nil, fmt.Errorf("parse job spec: %w", err)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &spec); err != nil {
+			return nil, fmt.Errorf("parse job spec: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unrecognized job spec extension %s", path)
+	}
+
+	if spec.Output == "" {
+		spec.Output = "pages"
+	}
+	return &spec, nil
+}
+
+// toOptions turns the job spec's scan parameters into the SANE Option
+// slice that parseOptions expects.
+func (j *JobSpec) toOptions() []Option {
+	var options []Option
+	if j.Resolution != 0 {
+		options = append(options, Option{Name: "resolution", Int: j.Resolution})
+	}
+	if j.Mode != "" {
+		options = append(options, Option{Name: "mode", String: j.Mode})
+	}
+	options = append(options, Option{Name: "source", String: saneSourceString(j.Source)})
+	if j.PageSize != "" {
+		options = append(options, Option{Name: "page-size", String: j.PageSize})
+	}
+	options = append(options, Option{Name: "preview", Bool: false})
+	return options
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vit1251/scan-server/config"
+)
+
+func TestJobSpecToOptionsDefaultsSourceToFlatbed(t *testing.T) {
+	j := &JobSpec{}
+	opts := j.toOptions()
+
+	found := false
+	for _, o := range opts {
+		if o.Name == "source" {
+			found = true
+			if o.String != "Flatbed" {
+				t.Errorf("toOptions() source = %q, want %q", o.String, "Flatbed")
+			}
+		}
+	}
+	if !found {
+		t.Fatal("toOptions() did not include a source option")
+	}
+}
+
+func TestJobSpecToOptionsOmitsUnsetResolutionAndMode(t *testing.T) {
+	j := &JobSpec{Source: "adf"}
+	opts := j.toOptions()
+
+	for _, o := range opts {
+		if o.Name == "resolution" || o.Name == "mode" {
+			t.Errorf("toOptions() included unset option %q", o.Name)
+		}
+	}
+}
+
+func TestJobSpecApplyProfileJobValueWinsOverProfile(t *testing.T) {
+	j := &JobSpec{Resolution: 1200}
+	j.applyProfile(config.Profile{Resolution: 300, Mode: "gray"})
+
+	if j.Resolution != 1200 {
+		t.Errorf("applyProfile() resolution = %d, want job spec's own 1200 to win", j.Resolution)
+	}
+	if j.Mode != "gray" {
+		t.Errorf("applyProfile() mode = %q, want profile default %q", j.Mode, "gray")
+	}
+}
+
+func TestLoadJobSpecYAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "job.yaml")
+	data := []byte("source: adf\nresolution: 300\nformat: png\n")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write test job spec: %v", err)
+	}
+
+	spec, err := LoadJobSpec(path)
+	if err != nil {
+		t.Fatalf("LoadJobSpec() error = %v", err)
+	}
+	if spec.Source != "adf" || spec.Resolution != 300 {
+		t.Errorf("LoadJobSpec() = %+v, want Source=adf Resolution=300", spec)
+	}
+	if spec.Output != "pages" {
+		t.Errorf("LoadJobSpec() Output = %q, want default %q", spec.Output, "pages")
+	}
+}
+
+func TestLoadJobSpecUnrecognizedExtension(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "job.ini")
+	if err := os.WriteFile(path, []byte("source=adf"), 0o644); err != nil {
+		t.Fatalf("write test job spec: %v", err)
+	}
+
+	if _, err := LoadJobSpec(path); err == nil {
+		t.Error("LoadJobSpec() error = nil, want an error for an unrecognized extension")
+	}
+}
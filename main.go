@@ -1,14 +1,19 @@
 package main
 
 import (
-	"log"
+	"flag"
 	"github.com/tjgq/sane"
+	"github.com/vit1251/scan-server/config"
+	"github.com/vit1251/scan-server/escl"
+	"github.com/vit1251/scan-server/imgproc"
+	"github.com/vit1251/scan-server/server"
 	"image"
 	"image/jpeg"
 	"image/png"
 	"golang.org/x/image/tiff"
 	"fmt"
 	"io"
+	"net/http"
 	"path/filepath"
 	"strings"
 	"os"
@@ -25,7 +30,12 @@ var unitName = map[sane.Unit]string{
 
 type EncodeFunc func(io.Writer, image.Image) error
 
-func pathToEncoder(path string) (EncodeFunc, error) {
+// pathToEncoder picks an EncodeFunc from path's extension. ocr only
+// affects ".pdf": when true, the page is run through tesseract and the
+// recognized text is embedded as an invisible, searchable layer. g4
+// only affects ".tif"/".tiff": when true, the page is binarized and
+// compressed as CCITT Group 4 instead of written uncompressed.
+func pathToEncoder(path string, ocr bool, g4 bool) (EncodeFunc, error) {
 	ext := strings.ToLower(filepath.Ext(path))
 	switch ext {
 	case ".png":
@@ -35,14 +45,50 @@ func pathToEncoder(path string) (EncodeFunc, error) {
 			return jpeg.Encode(w, m, nil)
 		}, nil
 	case ".tif", ".tiff":
+		if g4 {
+			return imgproc.EncodeCCITTG4TIFF, nil
+		}
 		return func(w io.Writer, m image.Image) error {
 			return tiff.Encode(w, m, nil)
 		}, nil
+	case ".pdf":
+		return func(w io.Writer, m image.Image) error {
+			return imgproc.EncodeSearchablePDF(w, m, ocr)
+		}, nil
 	default:
 		return nil, fmt.Errorf("unrecognized extension")
 	}
 }
 
+// port extracts the numeric port from a listen address like ":8090" or
+// "0.0.0.0:8090", for callers (such as the mDNS advertiser) that need
+// it as an int rather than a string.
+func port(addr string) int {
+	idx := strings.LastIndex(addr, ":")
+	if idx < 0 {
+		return 0
+	}
+	p := 0
+	fmt.Sscanf(addr[idx+1:], "%d", &p)
+	return p
+}
+
+// saneSourceString maps a profile/job spec source name ("flatbed",
+// "adf", "adf-duplex") to the SANE option string, shared by doScan and
+// JobSpec.toOptions so the CLI's mapping lives in one place. config's
+// sourceOptionString is a separate copy that returns "" for an unset
+// source.
+func saneSourceString(source string) string {
+	switch source {
+	case "adf":
+		return "ADF"
+	case "adf-duplex":
+		return "ADF Duplex"
+	default:
+		return "Flatbed"
+	}
+}
+
 func openDevice(name string) (*sane.Conn, error) {
 	c, err1 := sane.Open(name)
 	if err1 == nil {
@@ -61,142 +107,164 @@ func openDevice(name string) (*sane.Conn, error) {
 	return nil, fmt.Errorf("no device named %s", name)
 }
 
-func printConstraints(o sane.Option) {
+func formatConstraints(o sane.Option) string {
+    var b strings.Builder
     first := true
     if o.IsAutomatic {
-	print(" auto")
+	b.WriteString("auto")
 	first = false
     }
     if o.ConstrRange != nil {
 	if first {
-	    print(" %v..%v", o.ConstrRange.Min, o.ConstrRange.Max)
+	    fmt.Fprintf(&b, "%v..%v", o.ConstrRange.Min, o.ConstrRange.Max)
 	} else {
-	    print("|%v..%v", o.ConstrRange.Min, o.ConstrRange.Max)
+	    fmt.Fprintf(&b, "|%v..%v", o.ConstrRange.Min, o.ConstrRange.Max)
 	}
 	if (o.Type == sane.TypeInt && o.ConstrRange.Quant != 0) ||
 	    (o.Type == sane.TypeFloat && o.ConstrRange.Quant != 0.0) {
-	    print(" in steps of %v", o.ConstrRange.Quant)
+	    fmt.Fprintf(&b, " in steps of %v", o.ConstrRange.Quant)
 	}
     } else {
 	for _, v := range o.ConstrSet {
 	    if first {
-		print(" %v", v)
+		fmt.Fprintf(&b, "%v", v)
 		first = false
 	    } else {
-		print("|%v", v)
+		fmt.Fprintf(&b, "|%v", v)
 	    }
 	}
     }
+    return b.String()
 }
 
-func printOption(o sane.Option, v interface{}) {
-
-	log.Printf("-------------------------------------------------")
-
-	// Print option name
-	log.Printf("    -%s", o.Name)
+func printOption(o sane.Option, v interface{}, logger Logger) {
 
-	// Print constraints
-	printConstraints(o)
-
-	// Print current value
-	if v != nil {
-		log.Printf(" [%v]", v)
-	} else {
-		if !o.IsActive {
-			log.Printf(" [inactive]")
-		} else {
-			log.Printf(" [?]")
-		}
-	}
+	constraints := formatConstraints(o)
 
-	// Print unit
-	if name, ok := unitName[o.Unit]; ok {
-		log.Printf(" %s", name)
+	value := v
+	if value == nil && !o.IsActive {
+		value = "inactive"
 	}
-
-	// Print description
-	log.Printf("%s", o.Desc)
+	logger.Info("option", "name", o.Name, "value", value, "constraints", constraints, "unit", unitName[o.Unit], "desc", o.Desc)
 }
 
-func showOptions(c *sane.Conn) {
+func showOptions(c *sane.Conn, logger Logger) {
 
 	lastGroup := ""
-	log.Printf("Options for device %s:\n", c.Device)
+	logger.Info("options for device", "device", c.Device)
 	for _, o := range c.Options() {
 		if !o.IsSettable {
 			continue
 		}
 		if o.Group != lastGroup {
-			log.Printf("  %s:\n", o.Group)
+			logger.Info("option group", "group", o.Group)
 			lastGroup = o.Group
 		}
 		v, _ := c.GetOption(o.Name)
-		printOption(o, v)
+		printOption(o, v, logger)
 	}
 }
 
-func listDevices() {
-	devs, _ := sane.Devices()
+// defaultProfile is doScan's fallback scan configuration, used for
+// whatever a cfg-matched profile doesn't itself specify.
+var defaultProfile = config.Profile{Resolution: 600, Mode: "color", Source: "flatbed"}
+
+func listDevices(logger Logger, cfg *config.Config) error {
+	devs, err := sane.Devices()
+	if err != nil {
+		return &ScanError{Op: "list devices", Err: err}
+	}
 	if len(devs) == 0 {
-		log.Printf("No available devices.")
+		logger.Info("no available devices")
 	}
 	for _, d := range devs {
-		log.Printf("Device %s is a %s %s %s", d.Name, d.Vendor, d.Model, d.Type)
-		c, _ := openDevice(d.Name)
-		doScan(c, "1.jpg", nil)
+		logger.Info("found device", "name", d.Name, "vendor", d.Vendor, "model", d.Model, "type", d.Type)
+		c, err := openDevice(d.Name)
+		if err != nil {
+			logger.Error("open device failed", "device", d.Name, "err", err)
+			continue
+		}
+		var profile config.Profile
+		if cfg != nil {
+			profile, _ = cfg.ProfileForDevice(d.Name)
+		}
+		if err := doScan(c, "1.jpg", logger, imgproc.Pipeline{}, false, false, profile); err != nil {
+			logger.Error("scan failed", "device", d.Name, "err", err)
+		}
 		c.Close()
 	}
 
+	return nil
 }
 
-func doScan(c *sane.Conn, fileName string, optargs []string) {
+// doScan reads one page from c and encodes it to fileName. Its scan
+// parameters come from defaultProfile with profile's fields (if any
+// matched from a loaded config) applied on top, rather than hard-coded
+// values, so callers that resolve a config.Profile for the device get
+// the same resolution/mode/source handling runBatchScan and the HTTP
+// and eSCL servers already do.
+func doScan(c *sane.Conn, fileName string, logger Logger, pipeline imgproc.Pipeline, ocr bool, g4 bool, profile config.Profile) error {
 
-	enc, err := pathToEncoder(fileName)
+	enc, err := pathToEncoder(fileName, ocr, g4)
 	if err != nil {
-		panic(err)
+		return &ScanError{Op: "encode", Err: err}
 	}
 
 	stream, err := os.Create(fileName)
 	if err != nil {
-		panic(err)
+		return &ScanError{Op: "create output file", Err: err}
 	}
 	defer func() {
-		if err := stream.Close(); err != nil {
-			panic(err)
+		if cerr := stream.Close(); cerr != nil {
+			logger.Error("close output file failed", "file", fileName, "err", cerr)
 		}
 	}()
 
-	showOptions(c)
+	showOptions(c, logger)
+
+	merged := defaultProfile.Merge(profile)
 
 	var options []Option
 	options = append(options, Option{
 		Name: "resolution",
-		Int: 600,
+		Int: merged.Resolution,
+	})
+	options = append(options, Option{
+		Name:   "mode",
+		String: merged.Mode,
 	})
 	options = append(options, Option{
-		Name: "mode",
-		String: "color",
+		Name:   "source",
+		String: saneSourceString(merged.Source),
 	})
 	options = append(options, Option{
 		Name: "preview",
 		Bool: false,
 	})
 
-
 	if err := parseOptions(c, options); err != nil {
-		panic(err)
+		return err
 	}
 
 	img, err := c.ReadImage()
 	if err != nil {
-		panic(err)
+		return &ScanError{Op: "read image", Err: err}
+	}
+
+	processed, keep, err := pipeline.Apply(img)
+	if err != nil {
+		return &ScanError{Op: "post-process image", Err: err}
+	}
+	if !keep {
+		logger.Info("dropped blank page", "file", fileName)
+		return nil
 	}
 
-	if err := enc(stream, img); err != nil {
-		panic(err)
+	if err := enc(stream, processed); err != nil {
+		return &ScanError{Op: "encode image", Err: ErrEncodeFailed}
 	}
 
+	return nil
 }
 
 type Option struct {
@@ -215,7 +283,7 @@ func findOption(opts []sane.Option, name string) (*sane.Option, error) {
 	    return &o, nil
 	}
     }
-    return nil, fmt.Errorf("no such option")
+    return nil, &ScanError{Op: fmt.Sprintf("find option %s", name), Err: ErrOptionNotFound}
 }
 
 func parseOptions(c *sane.Conn, args []Option) error {
@@ -224,7 +292,7 @@ func parseOptions(c *sane.Conn, args []Option) error {
 
 	o, err := findOption(c.Options(), a.Name)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	var v interface{}
 	if o.IsAutomatic && a.Auto {
@@ -252,13 +320,107 @@ func parseOptions(c *sane.Conn, args []Option) error {
 
 func main() {
 
-	log.Printf("ScanServer v1.0.0")
+	jobPath := flag.String("job", "", "path to a job spec (YAML or JSON) describing a batch scan")
+	device := flag.String("device", "", "device name (or substring) to run the job against")
+	listen := flag.String("listen", "", "address to serve the HTTP API on, e.g. :8080")
+	esclListen := flag.String("escl", "", "address to serve eSCL (AirScan) on, e.g. :8090")
+	configPath := flag.String("config", "", "path to a TOML/YAML config file of device profiles")
+	profileName := flag.String("profile", "", "profile name to use from -config; defaults to the device's [device.<pattern>] mapping")
+	flag.Parse()
+
+	logger := NewDefaultLogger()
+	logger.Info("ScanServer v1.0.0")
 
 	if err1 := sane.Init(); err1 != nil {
-		panic(err1)
+		logger.Error("sane init failed", "err", err1)
+		os.Exit(1)
 	}
 	defer sane.Exit()
 
-	listDevices()
+	var cfg *config.Config
+	if *configPath != "" {
+		var err error
+		cfg, err = config.Load(*configPath)
+		if err != nil {
+			logger.Error("load config failed", "err", err)
+			os.Exit(1)
+		}
+	}
+
+	if *listen != "" {
+		srv := server.New(cfg)
+		mux := http.NewServeMux()
+		srv.Routes(mux)
+		logger.Info("listening", "addr", *listen)
+		if err := http.ListenAndServe(*listen, mux); err != nil {
+			logger.Error("http server failed", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *esclListen != "" {
+		esclSrv := escl.New(*device, cfg)
+		mux := http.NewServeMux()
+		esclSrv.Routes(mux)
+
+		zc, err := escl.Advertise("ScanServer", port(*esclListen), false)
+		if err != nil {
+			logger.Error("mdns advertise failed", "err", err)
+			os.Exit(1)
+		}
+		defer zc.Shutdown()
+
+		logger.Info("serving eSCL", "addr", *esclListen)
+		if err := http.ListenAndServe(*esclListen, mux); err != nil {
+			logger.Error("escl server failed", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *jobPath == "" && *configPath == "" {
+		if err := listDevices(logger, cfg); err != nil {
+			logger.Error("list devices failed", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var job *JobSpec
+	if *jobPath != "" {
+		var err error
+		job, err = LoadJobSpec(*jobPath)
+		if err != nil {
+			logger.Error("load job spec failed", "err", err)
+			os.Exit(1)
+		}
+	} else {
+		job = &JobSpec{Output: "pages", Format: "png", Dest: "."}
+	}
+
+	if cfg != nil {
+		profile, ok := cfg.Profile[*profileName]
+		if *profileName == "" {
+			profile, ok = cfg.ProfileForDevice(*device)
+		}
+		if !ok {
+			logger.Error("no matching profile", "profile", *profileName, "device", *device)
+			os.Exit(1)
+		}
+		job.applyProfile(profile)
+	}
+
+	c, err := openDevice(*device)
+	if err != nil {
+		logger.Error("open device failed", "device", *device, "err", err)
+		os.Exit(1)
+	}
+	defer c.Close()
+
+	if err := runBatchScan(c, job); err != nil {
+		logger.Error("batch scan failed", "err", err)
+		os.Exit(1)
+	}
 
 }
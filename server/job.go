@@ -0,0 +1,100 @@
+package server
+
+import (
+	"fmt"
+	"image"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type jobStatus string
+
+const (
+	jobPending jobStatus = "pending"
+	jobDone    jobStatus = "done"
+	jobFailed  jobStatus = "failed"
+)
+
+type job struct {
+	status jobStatus
+	format string // the format requested when the job was submitted
+	result image.Image
+	err    error
+}
+
+// jobQueue runs scans in the background and lets clients poll for the
+// result via GET /jobs/{id}, so a slow scan doesn't tie up an HTTP
+// request (or a client's connection) for its whole duration.
+type jobQueue struct {
+	mu     sync.Mutex
+	nextID int
+	jobs   map[string]*job
+}
+
+func newJobQueue() *jobQueue {
+	return &jobQueue{jobs: make(map[string]*job)}
+}
+
+// submit runs fn in its own goroutine and returns an id that GET
+// /jobs/{id} can later use to retrieve the result. format is the output
+// format the caller asked for, so handleJob can encode the result the
+// same way the synchronous path does.
+func (q *jobQueue) submit(format string, fn func() (image.Image, error)) string {
+	q.mu.Lock()
+	q.nextID++
+	id := strconv.Itoa(q.nextID)
+	q.jobs[id] = &job{status: jobPending, format: format}
+	q.mu.Unlock()
+
+	go func() {
+		img, err := fn()
+
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		j := q.jobs[id]
+		if err != nil {
+			j.status = jobFailed
+			j.err = err
+			return
+		}
+		j.status = jobDone
+		j.result = img
+	}()
+
+	return id
+}
+
+func (q *jobQueue) get(id string) (*job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j, ok := q.jobs[id]
+	return j, ok
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/jobs/")
+	if id == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	j, ok := s.jobs.get(id)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no such job %s", id), http.StatusNotFound)
+		return
+	}
+
+	switch j.status {
+	case jobPending:
+		writeJSON(w, http.StatusOK, map[string]string{"status": string(jobPending)})
+	case jobFailed:
+		writeJSON(w, http.StatusOK, map[string]string{"status": string(jobFailed), "error": j.err.Error()})
+	case jobDone:
+		w.Header().Set("Content-Type", contentTypeFor(j.format))
+		if err := encodeAs(j.format, w, j.result); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
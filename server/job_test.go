@@ -0,0 +1,103 @@
+package server
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"testing"
+)
+
+func TestJobQueueSubmitAndGet(t *testing.T) {
+	q := newJobQueue()
+	done := make(chan struct{})
+	id := q.submit("png", func() (image.Image, error) {
+		defer close(done)
+		return image.NewGray(image.Rect(0, 0, 1, 1)), nil
+	})
+	<-done
+
+	j, ok := q.get(id)
+	if !ok {
+		t.Fatalf("get(%q) found no job", id)
+	}
+	waitForStatus(t, q, id, jobDone)
+	if j.format != "png" {
+		t.Errorf("job.format = %q, want %q", j.format, "png")
+	}
+}
+
+func TestJobQueueSubmitFailure(t *testing.T) {
+	q := newJobQueue()
+	wantErr := errors.New("scan failed")
+	id := q.submit("png", func() (image.Image, error) {
+		return nil, wantErr
+	})
+
+	waitForStatus(t, q, id, jobFailed)
+	j, _ := q.get(id)
+	if j.err != wantErr {
+		t.Errorf("job.err = %v, want %v", j.err, wantErr)
+	}
+}
+
+func TestJobQueueGetUnknownID(t *testing.T) {
+	q := newJobQueue()
+	if _, ok := q.get("missing"); ok {
+		t.Error("get() found a job for an id that was never submitted")
+	}
+}
+
+// TestJobQueueConcurrentSubmit exercises q.mu: submitting many jobs at
+// once must hand out distinct ids and never corrupt q.jobs, even
+// though every submit and every background completion touches it from
+// its own goroutine.
+func TestJobQueueConcurrentSubmit(t *testing.T) {
+	q := newJobQueue()
+	const n = 50
+
+	ids := make([]string, n)
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			ids[i] = q.submit("png", func() (image.Image, error) {
+				return image.NewGray(image.Rect(0, 0, 1, 1)), nil
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, n)
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("submit() returned duplicate id %q", id)
+		}
+		seen[id] = true
+		waitForStatus(t, q, id, jobDone)
+	}
+}
+
+// waitForStatus polls q for id's terminal status, reading status and err
+// under q.mu since the background goroutine in submit writes them under
+// the same lock.
+func waitForStatus(t *testing.T, q *jobQueue, id string, want jobStatus) {
+	t.Helper()
+	for {
+		q.mu.Lock()
+		j, ok := q.jobs[id]
+		if !ok {
+			q.mu.Unlock()
+			t.Fatalf("get(%q) found no job", id)
+		}
+		status, err := j.status, j.err
+		q.mu.Unlock()
+
+		if status == want {
+			return
+		}
+		if status == jobFailed && want != jobFailed {
+			t.Fatalf("job %q failed: %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Package server exposes the scanner over HTTP: device discovery,
+// per-device options, and scan/job submission. It turns the scan-server
+// binary from a one-shot CLI into a long-running service that multiple
+// clients can hit concurrently.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/tjgq/sane"
+	"golang.org/x/image/tiff"
+
+	"github.com/vit1251/scan-server/config"
+)
+
+// Server holds the long-lived state needed to serve scan requests: the
+// job queue, one mutex per device (since a SANE connection cannot
+// service concurrent ReadImage calls), and an optional config of named
+// scan profiles a request can select by name or by device default.
+type Server struct {
+	cfg  *config.Config
+	jobs *jobQueue
+
+	mu      sync.Mutex
+	devLock map[string]*sync.Mutex
+}
+
+// New creates a Server ready to be mounted with Routes. cfg may be nil,
+// in which case scan requests must set every SANE option explicitly.
+func New(cfg *config.Config) *Server {
+	return &Server{
+		cfg:     cfg,
+		jobs:    newJobQueue(),
+		devLock: make(map[string]*sync.Mutex),
+	}
+}
+
+// Routes registers the server's endpoints on mux.
+func (s *Server) Routes(mux *http.ServeMux) {
+	mux.HandleFunc("/devices", s.handleDevices)
+	mux.HandleFunc("/devices/", s.handleDevice) // /devices/{name}/options, /devices/{name}/scan
+	mux.HandleFunc("/jobs/", s.handleJob)       // /jobs/{id}
+}
+
+func (s *Server) lockFor(name string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.devLock[name]
+	if !ok {
+		l = &sync.Mutex{}
+		s.devLock[name] = l
+	}
+	return l
+}
+
+func (s *Server) handleDevices(w http.ResponseWriter, r *http.Request) {
+	devs, err := sane.Devices()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, devs)
+}
+
+// handleDevice dispatches /devices/{name}/options and /devices/{name}/scan.
+func (s *Server) handleDevice(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/devices/")
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+	name, action := parts[0], parts[1]
+
+	switch {
+	case action == "options" && r.Method == http.MethodGet:
+		s.handleOptions(w, r, name)
+	case action == "scan" && r.Method == http.MethodPost:
+		s.handleScan(w, r, name)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) handleOptions(w http.ResponseWriter, r *http.Request, name string) {
+	c, err := sane.Open(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	defer c.Close()
+
+	writeJSON(w, http.StatusOK, c.Options())
+}
+
+// scanRequest is the body accepted by POST /devices/{name}/scan: an
+// optional named config profile, SANE option overrides applied on top
+// of it, the desired output format, and whether the caller wants the
+// result streamed back inline or run as a background job.
+type scanRequest struct {
+	Profile string       `json:"profile"` // config profile name; defaults to the device's [device.<pattern>] mapping
+	Options []scanOption `json:"options"`
+	Format  string       `json:"format"` // "png", "jpg", "tiff"
+	Async   bool         `json:"async"`
+}
+
+type scanOption struct {
+	Name   string  `json:"name"`
+	Bool   bool    `json:"bool,omitempty"`
+	Int    int     `json:"int,omitempty"`
+	Float  float64 `json:"float,omitempty"`
+	String string  `json:"string,omitempty"`
+	Auto   bool    `json:"auto,omitempty"`
+}
+
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request, name string) {
+	var req scanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid scan request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Format == "" {
+		if profile, ok := s.resolveProfile(name, req.Profile); ok {
+			req.Format = profile.Format
+		}
+	}
+	if req.Format == "" {
+		req.Format = "png"
+	}
+
+	if req.Async {
+		id := s.jobs.submit(req.Format, func() (image.Image, error) {
+			return s.scan(name, req)
+		})
+		writeJSON(w, http.StatusAccepted, map[string]string{"id": id})
+		return
+	}
+
+	img, err := s.scan(name, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeFor(req.Format))
+	if err := encodeAs(req.Format, w, img); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// scan opens the named device, applies the requested options and reads
+// a single image. It holds the device's mutex for the duration so two
+// requests against the same scanner never race on ReadImage.
+func (s *Server) scan(name string, req scanRequest) (image.Image, error) {
+	lock := s.lockFor(name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	c, err := sane.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	opts := c.Options()
+
+	if profile, ok := s.resolveProfile(name, req.Profile); ok {
+		if err := profile.Apply(c, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, o := range req.Options {
+		so, err := findSaneOption(opts, o.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		var v interface{}
+		if so.IsAutomatic && o.Auto {
+			v = sane.Auto
+		} else {
+			switch so.Type {
+			case sane.TypeBool:
+				v = o.Bool
+			case sane.TypeInt:
+				v = o.Int
+			case sane.TypeFloat:
+				v = o.Float
+			case sane.TypeString:
+				v = o.String
+			}
+		}
+		if _, err := c.SetOption(so.Name, v); err != nil {
+			return nil, fmt.Errorf("set option %s: %w", so.Name, err)
+		}
+	}
+
+	return c.ReadImage()
+}
+
+// resolveProfile picks the config profile a scan request should apply:
+// reqProfile by name if given, else the device's [device.<pattern>]
+// default. It reports false when the server has no config loaded or no
+// profile matches, in which case the caller falls back to req.Options alone.
+func (s *Server) resolveProfile(device, reqProfile string) (config.Profile, bool) {
+	if s.cfg == nil {
+		return config.Profile{}, false
+	}
+	if reqProfile != "" {
+		p, ok := s.cfg.Profile[reqProfile]
+		return p, ok
+	}
+	return s.cfg.ProfileForDevice(device)
+}
+
+// findSaneOption looks up name's real SANE option, the way main.go's
+// findOption does, so the caller's Int/Float/String/Bool fields are
+// interpreted by the option's actual type instead of guessed from
+// which field happens to be non-zero.
+func findSaneOption(opts []sane.Option, name string) (*sane.Option, error) {
+	for _, o := range opts {
+		if o.Name == name {
+			return &o, nil
+		}
+	}
+	return nil, fmt.Errorf("no such option %s", name)
+}
+
+// contentTypeFor mirrors pathToEncoder's extension handling for the
+// formats the HTTP API accepts.
+func contentTypeFor(format string) string {
+	switch strings.ToLower(format) {
+	case "jpg", "jpeg":
+		return "image/jpeg"
+	case "tif", "tiff":
+		return "image/tiff"
+	default:
+		return "image/png"
+	}
+}
+
+func encodeAs(format string, w io.Writer, img image.Image) error {
+	switch strings.ToLower(format) {
+	case "jpg", "jpeg":
+		return jpeg.Encode(w, img, nil)
+	case "tif", "tiff":
+		return tiff.Encode(w, img, nil)
+	default:
+		return png.Encode(w, img)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
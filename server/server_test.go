@@ -0,0 +1,59 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/vit1251/scan-server/config"
+)
+
+func TestContentTypeFor(t *testing.T) {
+	cases := map[string]string{
+		"jpg":  "image/jpeg",
+		"jpeg": "image/jpeg",
+		"tif":  "image/tiff",
+		"tiff": "image/tiff",
+		"png":  "image/png",
+		"":     "image/png",
+	}
+	for format, want := range cases {
+		if got := contentTypeFor(format); got != want {
+			t.Errorf("contentTypeFor(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestResolveProfileNoConfig(t *testing.T) {
+	s := New(nil)
+	if _, ok := s.resolveProfile("Canon", ""); ok {
+		t.Error("resolveProfile() matched with no config loaded")
+	}
+}
+
+func TestResolveProfileByName(t *testing.T) {
+	cfg := &config.Config{Profile: map[string]config.Profile{"receipt": {Resolution: 300}}}
+	s := New(cfg)
+
+	p, ok := s.resolveProfile("Canon", "receipt")
+	if !ok {
+		t.Fatal("resolveProfile() found no profile named \"receipt\"")
+	}
+	if p.Resolution != 300 {
+		t.Errorf("resolveProfile() resolution = %d, want 300", p.Resolution)
+	}
+}
+
+func TestResolveProfileFallsBackToDevice(t *testing.T) {
+	cfg := &config.Config{
+		Profile: map[string]config.Profile{"receipt": {Resolution: 300}},
+		Device:  map[string]string{"ScanSnap": "receipt"},
+	}
+	s := New(cfg)
+
+	p, ok := s.resolveProfile("ScanSnap iX500", "")
+	if !ok {
+		t.Fatal("resolveProfile() found no device default")
+	}
+	if p.Resolution != 300 {
+		t.Errorf("resolveProfile() resolution = %d, want 300", p.Resolution)
+	}
+}